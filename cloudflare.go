@@ -2,24 +2,40 @@ package cloudflare
 import (
 	"fmt"
 	"encoding/json"
+	"math/rand"
 	"net/http"
 	"io"
+	"strconv"
 	"strings"
 	"net/url"
 	"crypto/tls"
+	"time"
 )
 
 const ContentTypeApplicationJson    string = "application/json"
 const ApiUrlBase                    string = "https://api.cloudflare.com/client/v4"
 const APiUrlZones                   string = "/zones/"
 const ApiUrlDnsRecords              string = "/dns_records/"
+const ApiUrlUserTokensVerify        string = "/user/tokens/verify"
 
-// Base Client config, email and api key are mandatory
+// Default retry behaviour for BaseSvc.Invoke, overridable via Config.SetMaxRetries
+const DefaultMaxRetries             int           = 4
+const DefaultRetryWaitMin           time.Duration = 1 * time.Second
+const DefaultRetryWaitMax           time.Duration = 30 * time.Second
+
+// maxRetriesUnset is Config.maxRetries' zero state, distinguishing "never
+// called SetMaxRetries" (DefaultMaxRetries applies) from an explicit
+// SetMaxRetries(0) (retries disabled)
+const maxRetriesUnset int = -1
+
+// Base Client config, either email+key (global API key) or token (API Token) must be configured
 type Config struct {
 	email              string
 	key                string
+	token              string
 	contentType        string
 	insecureSkipVerify bool
+	maxRetries         int
 }
 // Base Service, contains config and defines common methods
 type BaseSvc		struct {
@@ -33,6 +49,18 @@ type ZonesSvc       struct {
 type DNSRecordsSvc  struct {
 	BaseSvc
 }
+// Tokens Service
+type TokensSvc      struct {
+	BaseSvc
+}
+
+// TokenStatus type, result of a VerifyToken call. /user/tokens/verify only
+// ever returns id+status - it doesn't echo the token's scopes/expiry, so
+// this type doesn't claim fields the API doesn't send
+type TokenStatus struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
 
 // Core Type decelerations
 
@@ -46,6 +74,29 @@ type Zone struct {
 	Paused              bool
 	OriginalNameServers []string `json:"original_name_servers"`
 	DevelopmentMode     uint64   `json:"development_mode"`
+	AccountId           string   `json:"-"`
+	Plan                string   `json:"-"`
+}
+
+// zoneWire mirrors the API's wire shape for Zone, whose account and plan
+// fields arrive as nested objects rather than flat strings
+type zoneWire struct {
+	Zone
+	Account struct {
+				 Id string `json:"id"`
+			 } `json:"account"`
+	Plan struct {
+			 Name string `json:"name"`
+		 } `json:"plan"`
+}
+
+// PurgeOptions type, controls what CacheSvc.PurgeCache clears
+type PurgeOptions struct {
+	PurgeEverything bool     `json:"purge_everything,omitempty"`
+	Files           []string `json:"files,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Hosts           []string `json:"hosts,omitempty"`
+	Prefixes        []string `json:"prefixes,omitempty"`
 }
 
 // DNSRecord type
@@ -83,6 +134,41 @@ type Message struct {
 			   } `json:"result_info"`
 }
 
+// APIError represents a single error entry from Message.Errors, including
+// Cloudflare's error_chain of underlying causes (e.g. which specific scope
+// a token was missing)
+type APIError struct {
+	Code       int        `json:"code"`
+	Message    string     `json:"message"`
+	ErrorChain []APIError `json:"error_chain,omitempty"`
+}
+
+// String Implementation, includes the error chain when present
+func (e APIError) String() string {
+	s := fmt.Sprintf("%d: %s", e.Code, e.Message)
+	if len(e.ErrorChain) == 0 {
+		return s
+	}
+	causes := make([]string, len(e.ErrorChain))
+	for i, cause := range e.ErrorChain {
+		causes[i] = cause.String()
+	}
+	return s + " (caused by: " + strings.Join(causes, "; ") + ")"
+}
+
+// APIErrors is the decoded form of Message.Errors, returned as the error
+// from Decode/InvokePaged when the API reports Success == false
+type APIErrors []APIError
+
+// Error Implementation, chains the individual API error messages together
+func (e APIErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, apiErr := range e {
+		parts[i] = apiErr.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
 // Helper method for Object to Json String
 func JsonString(this interface{}) string {
 	out, err := json.Marshal(this)
@@ -111,7 +197,28 @@ func NewConfig(email string, key string, contentType string, insecureSkipVerify
 		contentType = ContentTypeApplicationJson
 	}
 
-	return &Config{email: email, key: key, contentType: contentType, insecureSkipVerify: insecureSkipVerify}
+	return &Config{email: email, key: key, contentType: contentType, insecureSkipVerify: insecureSkipVerify, maxRetries: maxRetriesUnset}
+}
+
+// Config Factory for API Token auth, defaults to application/json type
+//	Usage:
+//		config := cloudflare.NewConfigWithToken(token, "", false)
+func NewConfigWithToken(token string, contentType string, insecureSkipVerify bool) *Config {
+	if token == "" {
+		return nil
+	}
+	if contentType == "" {
+		contentType = ContentTypeApplicationJson
+	}
+
+	return &Config{token: token, contentType: contentType, insecureSkipVerify: insecureSkipVerify, maxRetries: maxRetriesUnset}
+}
+
+// SetMaxRetries overrides the default number of retry attempts Invoke makes
+// on HTTP 429/5xx responses before giving up
+func (config *Config) SetMaxRetries(maxRetries int) *Config {
+	config.maxRetries = maxRetries
+	return config
 }
 
 // ZonesSvc Factory, pass config to inner BaseSvc object
@@ -122,19 +229,96 @@ func (config *Config) GetZonesSvc() *ZonesSvc {
 func (config *Config) GetDNSRecordsSvc() *DNSRecordsSvc {
 	return &DNSRecordsSvc{BaseSvc: BaseSvc{config: config}}
 }
+// TokensSvc Factory, pass config to inner BaseSvc object
+func (config *Config) GetTokensSvc() *TokensSvc {
+	return &TokensSvc{BaseSvc: BaseSvc{config: config}}
+}
+
+// retryingTransport wraps a RoundTripper and retries on HTTP 429/5xx
+// responses, honoring Retry-After / X-RateLimit-Reset when present and
+// falling back to a capped exponential backoff with jitter otherwise
+type retryingTransport struct {
+	transport  http.RoundTripper
+	maxRetries int
+}
+
+// RoundTrip Implementation
+func (rt *retryingTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	hasBody := req.Body != nil
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && hasBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, berr := req.GetBody()
+			if berr != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+
+		resp, err = rt.transport.RoundTrip(req)
+		if err != nil || !isRetryableResponse(resp) || attempt >= rt.maxRetries {
+			return resp, err
+		}
+
+		time.Sleep(retryDelay(attempt, resp))
+		resp.Body.Close()
+	}
+}
+
+// isRetryableResponse reports whether resp is a rate-limited or transient server error
+func isRetryableResponse(resp *http.Response) bool {
+	return resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500)
+}
+
+// retryDelay computes how long to wait before the next attempt, preferring
+// the server supplied Retry-After header, falling back to Cloudflare's
+// X-RateLimit-Reset (a unix epoch second to wait until), and otherwise
+// using exponential backoff with jitter, capped at DefaultRetryWaitMax
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			if seconds, err := strconv.Atoi(retryAfter); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+		if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
+			if epoch, err := strconv.ParseInt(reset, 10, 64); err == nil {
+				if wait := time.Until(time.Unix(epoch, 0)); wait > 0 {
+					return wait
+				}
+			}
+		}
+	}
+
+	wait := DefaultRetryWaitMin * time.Duration(1<<uint(attempt))
+	if wait > DefaultRetryWaitMax {
+		wait = DefaultRetryWaitMax
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2+1)))
+}
 
 // Utility function for executing the required http command
 func (baseSvc *BaseSvc) Invoke(method string, urlStr string, body io.Reader) (response *http.Response, error error) {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: baseSvc.config.insecureSkipVerify},
 	}
-	client := &http.Client{Transport: tr}
+	maxRetries := baseSvc.config.maxRetries
+	if maxRetries == maxRetriesUnset {
+		maxRetries = DefaultMaxRetries
+	}
+	client := &http.Client{Transport: &retryingTransport{transport: tr, maxRetries: maxRetries}}
 	req, err := http.NewRequest(method, urlStr, body)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("X-Auth-Email", baseSvc.config.email)
-	req.Header.Add("X-Auth-Key", baseSvc.config.key)
+	if baseSvc.config.token != "" {
+		req.Header.Add("Authorization", "Bearer "+baseSvc.config.token)
+	} else {
+		req.Header.Add("X-Auth-Email", baseSvc.config.email)
+		req.Header.Add("X-Auth-Key", baseSvc.config.key)
+	}
 	req.Header.Add("Content-Type", baseSvc.config.contentType)
 
 	return client.Do(req)
@@ -148,10 +332,87 @@ func (baseSvc *BaseSvc) Decode(resp *http.Response, result interface{}) error {
 	var msg Message
 	dec := json.NewDecoder(resp.Body)
 	err := dec.Decode(&msg)
-	if msg.Success {
-		err = json.Unmarshal(msg.Result, result)
+	if err != nil {
+		return err
+	}
+	if !msg.Success {
+		return decodeAPIErrors(msg.Errors)
+	}
+	return json.Unmarshal(msg.Result, result)
+}
+
+// decodeAPIErrors un-marshals a Message.Errors payload into APIErrors,
+// falling back to a generic error if the payload itself can't be parsed
+func decodeAPIErrors(raw json.RawMessage) error {
+	var apiErrs APIErrors
+	if err := json.Unmarshal(raw, &apiErrs); err != nil || len(apiErrs) == 0 {
+		return fmt.Errorf("cloudflare: request failed with no decodable error detail")
 	}
-	return err
+	return apiErrs
+}
+
+// InvokePaged repeatedly invokes urlStr, incrementing the "page" query
+// parameter, and calls pageFn with each decoded Message until
+// result_info.total_pages is exhausted
+//	Usage:
+//		var zones []Zone
+//		zonesSvc.InvokePaged("GET", cloudflare.ApiUrlBase+cloudflare.APiUrlZones, nil, func(msg *cloudflare.Message) error {
+//			var page []Zone
+//			if err := json.Unmarshal(msg.Result, &page); err != nil {
+//				return err
+//			}
+//			zones = append(zones, page...)
+//			return nil
+//		})
+func (baseSvc *BaseSvc) InvokePaged(method string, urlStr string, body io.Reader, pageFn func(msg *Message) error) error {
+	for page := 1; ; page++ {
+		resp, err := baseSvc.Invoke(method, withPageParam(urlStr, page), body)
+		if resp == nil {
+			return err
+		}
+		var msg Message
+		dec := json.NewDecoder(resp.Body)
+		err = dec.Decode(&msg)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if !msg.Success {
+			return decodeAPIErrors(msg.Errors)
+		}
+		if err := pageFn(&msg); err != nil {
+			return err
+		}
+		if msg.ResultInfo.TotalPages == 0 || uint64(page) >= msg.ResultInfo.TotalPages {
+			return nil
+		}
+	}
+}
+
+// withPageParam adds (or overrides) the "page" query parameter on urlStr
+func withPageParam(urlStr string, page int) string {
+	separator := "?"
+	if strings.Contains(urlStr, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%spage=%d", urlStr, separator, page)
+}
+
+// Verify the configured API Token and return its id and status. Cloudflare
+// doesn't return the token's scopes here - to check permissions, look the
+// token up by VerifyToken's Id via the tokens API instead
+//	Usage:
+//		config := cloudflare.NewConfigWithToken(token, "", false)
+//		tokensSvc := config.GetTokensSvc()
+//		status, err := tokensSvc.VerifyToken()
+func (tokensSvc *TokensSvc) VerifyToken() (status TokenStatus, err error) {
+	resp, err := tokensSvc.Invoke("GET", ApiUrlBase+ApiUrlUserTokensVerify, nil)
+	if resp == nil {
+		return TokenStatus{}, err
+	}
+	defer resp.Body.Close()
+	err = tokensSvc.Decode(resp, &status)
+	return status, err
 }
 
 // Get all zones
@@ -166,10 +427,131 @@ func (zonesSvc *ZonesSvc) Get() (zones []Zone, err error) {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	err = zonesSvc.Decode(resp, &zones)
+	var wire []zoneWire
+	err = zonesSvc.Decode(resp, &wire)
+	return flattenZoneWires(wire), err
+}
+
+// Get all zones, transparently walking every page of results
+//	Usage:
+//		zonesSvc := config.GetZonesSvc()
+//		zones, err := zonesSvc.GetAll()
+func (zonesSvc *ZonesSvc) GetAll() (zones []Zone, err error) {
+	err = zonesSvc.InvokePaged("GET", ApiUrlBase+APiUrlZones, nil, func(msg *Message) error {
+		var page []zoneWire
+		if uerr := json.Unmarshal(msg.Result, &page); uerr != nil {
+			return uerr
+		}
+		zones = append(zones, flattenZoneWires(page)...)
+		return nil
+	})
 	return zones, err
 }
 
+// flattenZoneWires copies the nested account/plan fields of each zoneWire
+// onto its embedded Zone's flat AccountId/Plan fields
+func flattenZoneWires(wire []zoneWire) (zones []Zone) {
+	for _, w := range wire {
+		zone := w.Zone
+		zone.AccountId = w.Account.Id
+		zone.Plan = w.Plan.Name
+		zones = append(zones, zone)
+	}
+	return zones
+}
+
+// Create a new zone
+//	Usage:
+//		zonesSvc := config.GetZonesSvc()
+//		zone, err := zonesSvc.Create("example.com", accountId, true, "full")
+func (zonesSvc *ZonesSvc) Create(name string, accountId string, jumpStart bool, zoneType string) (zone Zone, err error) {
+	body := struct {
+		Name      string `json:"name"`
+		Account   struct {
+					   Id string `json:"id"`
+				   } `json:"account"`
+		JumpStart bool   `json:"jump_start"`
+		Type      string `json:"type,omitempty"`
+	}{Name: name, JumpStart: jumpStart, Type: zoneType}
+	body.Account.Id = accountId
+
+	resp, err := zonesSvc.Invoke("POST", ApiUrlBase+APiUrlZones, strings.NewReader(JsonString(body)))
+	if resp == nil {
+		return Zone{}, err
+	}
+	defer resp.Body.Close()
+	var wire zoneWire
+	err = zonesSvc.Decode(resp, &wire)
+	wire.Zone.AccountId = wire.Account.Id
+	wire.Zone.Plan = wire.Plan.Name
+	return wire.Zone, err
+}
+
+// Delete a zone
+//	Usage:
+//		zonesSvc := config.GetZonesSvc()
+//		id, err := zonesSvc.Delete(zone.Id)
+func (zonesSvc *ZonesSvc) Delete(zoneId string) (id string, err error) {
+	resp, err := zonesSvc.Invoke("DELETE", ApiUrlBase+APiUrlZones+zoneId, nil)
+	if resp == nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var deleted struct {
+		Id string `json:"id"`
+	}
+	err = zonesSvc.Decode(resp, &deleted)
+	return deleted.Id, err
+}
+
+// Details fetches a single zone by id
+//	Usage:
+//		zonesSvc := config.GetZonesSvc()
+//		zone, err := zonesSvc.Details(zoneId)
+func (zonesSvc *ZonesSvc) Details(zoneId string) (zone Zone, err error) {
+	resp, err := zonesSvc.Invoke("GET", ApiUrlBase+APiUrlZones+zoneId, nil)
+	if resp == nil {
+		return Zone{}, err
+	}
+	defer resp.Body.Close()
+	var wire zoneWire
+	err = zonesSvc.Decode(resp, &wire)
+	wire.Zone.AccountId = wire.Account.Id
+	wire.Zone.Plan = wire.Plan.Name
+	return wire.Zone, err
+}
+
+// CheckActivation kicks off Cloudflare's re-check of the zone's nameserver activation status
+//	Usage:
+//		zonesSvc := config.GetZonesSvc()
+//		zone, err := zonesSvc.CheckActivation(zoneId)
+func (zonesSvc *ZonesSvc) CheckActivation(zoneId string) (zone Zone, err error) {
+	resp, err := zonesSvc.Invoke("PUT", ApiUrlBase+APiUrlZones+zoneId+"/activation_check", nil)
+	if resp == nil {
+		return Zone{}, err
+	}
+	defer resp.Body.Close()
+	var wire zoneWire
+	err = zonesSvc.Decode(resp, &wire)
+	wire.Zone.AccountId = wire.Account.Id
+	wire.Zone.Plan = wire.Plan.Name
+	return wire.Zone, err
+}
+
+// PurgeCache purges cached content for a zone, per the given PurgeOptions
+//	Usage:
+//		zonesSvc := config.GetZonesSvc()
+//		err := zonesSvc.PurgeCache(zoneId, cloudflare.PurgeOptions{PurgeEverything: true})
+func (zonesSvc *ZonesSvc) PurgeCache(zoneId string, opts PurgeOptions) (err error) {
+	resp, err := zonesSvc.Invoke("POST", ApiUrlBase+APiUrlZones+zoneId+"/purge_cache", strings.NewReader(JsonString(opts)))
+	if resp == nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct{}
+	return zonesSvc.Decode(resp, &result)
+}
+
 // Get all DNSRecords for zone
 //	Usage:
 //		dnsSvc := config.GetDNSRecordsSvc()
@@ -184,6 +566,22 @@ func (dnsSvc DNSRecordsSvc) Get(zoneId string) (dnsRecords []DNSRecord, err erro
 	return dnsRecords, err
 }
 
+// Get all DNSRecords for zone, transparently walking every page of results
+//	Usage:
+//		dnsSvc := config.GetDNSRecordsSvc()
+//		dnsRecords, err := dnsSvc.GetAll(zone.Id)
+func (dnsSvc DNSRecordsSvc) GetAll(zoneId string) (dnsRecords []DNSRecord, err error) {
+	err = dnsSvc.InvokePaged("GET", ApiUrlBase+APiUrlZones+zoneId+ApiUrlDnsRecords, nil, func(msg *Message) error {
+		var page []DNSRecord
+		if uerr := json.Unmarshal(msg.Result, &page); uerr != nil {
+			return uerr
+		}
+		dnsRecords = append(dnsRecords, page...)
+		return nil
+	})
+	return dnsRecords, err
+}
+
 // Search DNSRecords for zone
 //	Usage:
 //		dnsSvc := config.GetDNSRecordsSvc()
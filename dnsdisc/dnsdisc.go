@@ -0,0 +1,222 @@
+// Package dnsdisc publishes EIP-1459 DNS discovery-tree records (the
+// format used by devp2p node discovery) as TXT records in a Cloudflare
+// zone, using cloudflare.DNSRecordsSvc as the backend.
+package dnsdisc
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JonSmoot/cloudflare-go"
+)
+
+// txtMaxLength is the practical limit Cloudflare (and most resolvers)
+// allow per TXT record string before they start getting split
+const txtMaxLength = 370
+
+const rootRecordPrefix = "enrtree-root:v1"
+const branchRecordPrefix = "enrtree-branch:"
+
+// Publish fully republishes the discovery tree for enrs/links under domain
+// in zoneId, deleting and recreating every record this package manages
+//	Usage:
+//		dnsSvc := config.GetDNSRecordsSvc()
+//		err := dnsdisc.Publish(dnsSvc, zoneId, "nodes.example.org", enrs, links, key, seq)
+func Publish(dnsSvc *cloudflare.DNSRecordsSvc, zoneId string, domain string, enrs []string, links []string, key *ecdsa.PrivateKey, seq int64) error {
+	return reconcile(dnsSvc, zoneId, domain, enrs, links, key, seq, true)
+}
+
+// Sync publishes the discovery tree for enrs/links under domain in zoneId,
+// diffing against the records already published there and issuing only
+// the Create/Update/Delete calls needed to bring it up to date
+//	Usage:
+//		dnsSvc := config.GetDNSRecordsSvc()
+//		err := dnsdisc.Sync(dnsSvc, zoneId, "nodes.example.org", enrs, links, key, seq)
+func Sync(dnsSvc *cloudflare.DNSRecordsSvc, zoneId string, domain string, enrs []string, links []string, key *ecdsa.PrivateKey, seq int64) error {
+	return reconcile(dnsSvc, zoneId, domain, enrs, links, key, seq, false)
+}
+
+// reconcile computes the desired tree, fetches the TXT records currently
+// published under domain, and issues the minimal set of Create/Update/
+// Delete calls to bring the zone in line. When fullReplace is set every
+// record this package manages is deleted first instead of diffed.
+func reconcile(dnsSvc *cloudflare.DNSRecordsSvc, zoneId string, domain string, enrs []string, links []string, key *ecdsa.PrivateKey, seq int64, fullReplace bool) error {
+	desired, err := buildTree(domain, enrs, links, key, seq)
+	if err != nil {
+		return err
+	}
+
+	// Search only ever returns a single page unless explicitly paged, and a
+	// tree's whole point is to span more records than fit on one page, so
+	// walk every page via GetAll instead.
+	existing, err := dnsSvc.GetAll(zoneId)
+	if err != nil {
+		return err
+	}
+	existingByName := make(map[string]cloudflare.DNSRecord)
+	for _, rec := range existing {
+		if rec.Type != "TXT" {
+			continue
+		}
+		if rec.Name == domain || strings.HasSuffix(rec.Name, "."+domain) {
+			existingByName[rec.Name] = rec
+		}
+	}
+
+	if fullReplace {
+		for name, rec := range existingByName {
+			if _, err := dnsSvc.Delete(zoneId, &rec); err != nil {
+				return err
+			}
+			delete(existingByName, name)
+		}
+	}
+
+	for name, content := range desired {
+		if rec, ok := existingByName[name]; ok {
+			if rec.Content != content {
+				rec.Content = content
+				if _, err := dnsSvc.Update(zoneId, &rec); err != nil {
+					return err
+				}
+			}
+			delete(existingByName, name)
+			continue
+		}
+		record := &cloudflare.DNSRecord{Type: "TXT", Name: name, Content: content}
+		if _, err := dnsSvc.Create(zoneId, record); err != nil {
+			return err
+		}
+	}
+
+	for _, rec := range existingByName {
+		if _, err := dnsSvc.Delete(zoneId, &rec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildTree computes every TXT record (root, branches, leaves) the tree
+// needs, keyed by fully-qualified label
+func buildTree(domain string, enrs []string, links []string, key *ecdsa.PrivateKey, seq int64) (map[string]string, error) {
+	leaves := make(map[string]string)
+	eRoot, eEntries := buildSubtree(enrLeaves(enrs))
+	lRoot, lEntries := buildSubtree(linkLeaves(links))
+
+	for hash, text := range eEntries {
+		leaves[hash+"."+domain] = text
+	}
+	for hash, text := range lEntries {
+		leaves[hash+"."+domain] = text
+	}
+
+	root, err := signRoot(key, eRoot, lRoot, seq)
+	if err != nil {
+		return nil, err
+	}
+	leaves[domain] = root
+	return leaves, nil
+}
+
+// enrLeaves prefixes each raw ENR payload with the "enr:" leaf marker
+func enrLeaves(enrs []string) []string {
+	leaves := make([]string, len(enrs))
+	for i, e := range enrs {
+		leaves[i] = "enr:" + e
+	}
+	return leaves
+}
+
+// linkLeaves returns links unmodified, they already carry the "enrtree://" marker
+func linkLeaves(links []string) []string {
+	leaves := make([]string, len(links))
+	copy(leaves, links)
+	return leaves
+}
+
+// buildSubtree deterministically groups leaf records into enrtree-branch
+// records, keeping each branch under txtMaxLength, and returns the root
+// hash together with every record (leaves and branches) keyed by hash
+func buildSubtree(leaves []string) (rootHash string, records map[string]string) {
+	records = make(map[string]string)
+	if len(leaves) == 0 {
+		return "", records
+	}
+
+	sorted := append([]string(nil), leaves...)
+	sort.Strings(sorted)
+
+	hashes := make([]string, len(sorted))
+	for i, leaf := range sorted {
+		h := subtreeHash(leaf)
+		records[h] = leaf
+		hashes[i] = h
+	}
+
+	for len(hashes) > 1 {
+		hashes = groupIntoBranches(hashes, records)
+	}
+	return hashes[0], records
+}
+
+// groupIntoBranches folds a level of hashes into enrtree-branch records,
+// returning the hashes of those branch records for the next level up
+func groupIntoBranches(hashes []string, records map[string]string) []string {
+	maxChildren := maxBranchChildren()
+	var next []string
+	for len(hashes) > 0 {
+		n := maxChildren
+		if n > len(hashes) {
+			n = len(hashes)
+		}
+		group := hashes[:n]
+		hashes = hashes[n:]
+
+		branch := branchRecordPrefix + strings.Join(group, ",")
+		h := subtreeHash(branch)
+		records[h] = branch
+		next = append(next, h)
+	}
+	return next
+}
+
+// maxBranchChildren is how many child hashes fit in one branch record
+// without exceeding txtMaxLength
+func maxBranchChildren() int {
+	hashLen := base32.StdEncoding.WithPadding(base32.NoPadding).EncodedLen(16)
+	n := (txtMaxLength - len(branchRecordPrefix) + 1) / (hashLen + 1)
+	if n < 2 {
+		n = 2
+	}
+	return n
+}
+
+// subtreeHash is the base32(no-padding) of the first 16 bytes of the
+// keccak256 hash of text, used as both the record's label and the
+// reference other records use to point at it
+func subtreeHash(text string) string {
+	sum := keccak256([]byte(text))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum[:16])
+}
+
+// signRoot builds the unsigned "enrtree-root:v1 e=... l=... seq=..."
+// record and appends a base64url secp256k1 signature over it
+func signRoot(key *ecdsa.PrivateKey, eRoot string, lRoot string, seq int64) (string, error) {
+	unsigned := fmt.Sprintf("%s e=%s l=%s seq=%d", rootRecordPrefix, eRoot, lRoot, seq)
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, keccak256([]byte(unsigned)))
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return unsigned + " sig=" + base64.RawURLEncoding.EncodeToString(sig), nil
+}
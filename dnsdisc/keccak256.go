@@ -0,0 +1,103 @@
+package dnsdisc
+
+import "encoding/binary"
+
+// keccak256 is a from-scratch, dependency-free implementation of the
+// original (pre-NIST) Keccak-256 sponge - the hash EIP-1459 and the rest
+// of devp2p actually specify, which differs from stdlib-adjacent SHA3-256
+// only in its padding byte (0x01 instead of 0x06). It exists so this
+// package doesn't need golang.org/x/crypto, which this module has no
+// go.mod/go.sum to pin.
+func keccak256(data []byte) []byte {
+	const rate = 136 // 1600-bit state, 512-bit capacity, in bytes
+
+	var state [25]uint64
+	for len(data) >= rate {
+		absorb(&state, data[:rate])
+		keccakF1600(&state)
+		data = data[rate:]
+	}
+
+	var block [rate]byte
+	copy(block[:], data)
+	block[len(data)] ^= 0x01
+	block[rate-1] ^= 0x80
+	absorb(&state, block[:])
+	keccakF1600(&state)
+
+	out := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], state[i])
+	}
+	return out
+}
+
+// absorb XORs a full rate-sized block into the front of state
+func absorb(state *[25]uint64, block []byte) {
+	for i := 0; i < len(block)/8; i++ {
+		state[i] ^= binary.LittleEndian.Uint64(block[i*8:])
+	}
+}
+
+// keccakRC holds the 24 round constants of the iota step
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotc holds the rho step's per-lane rotation offsets, indexed as
+// state[x+5*y]
+var keccakRotc = [25]uint{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccakF1600 applies the 24-round Keccak-f[1600] permutation to state in place
+func keccakF1600(state *[25]uint64) {
+	var b [25]uint64
+	for round := 0; round < 24; round++ {
+		// theta
+		var c [5]uint64
+		for x := 0; x < 5; x++ {
+			c[x] = state[x] ^ state[x+5] ^ state[x+10] ^ state[x+15] ^ state[x+20]
+		}
+		var d [5]uint64
+		for x := 0; x < 5; x++ {
+			d[x] = c[(x+4)%5] ^ rotl64(c[(x+1)%5], 1)
+		}
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				state[x+5*y] ^= d[x]
+			}
+		}
+
+		// rho + pi
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = rotl64(state[x+5*y], keccakRotc[x+5*y])
+			}
+		}
+
+		// chi
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 5; x++ {
+				state[x+5*y] = b[x+5*y] ^ (^b[(x+1)%5+5*y] & b[(x+2)%5+5*y])
+			}
+		}
+
+		// iota
+		state[0] ^= keccakRC[round]
+	}
+}
+
+// rotl64 rotates v left by n bits within a 64-bit lane
+func rotl64(v uint64, n uint) uint64 {
+	return (v << n) | (v >> (64 - n))
+}
@@ -0,0 +1,107 @@
+package cloudflare
+import (
+	"strings"
+)
+
+const ApiUrlPageRules string = "/pagerules/"
+
+// PageRulesSvc Service
+type PageRulesSvc struct {
+	BaseSvc
+}
+
+// PageRuleTarget type, the URL pattern a PageRule matches against
+type PageRuleTarget struct {
+	Target     string `json:"target"`
+	Constraint struct {
+				   Operator string `json:"operator"`
+				   Value    string `json:"value"`
+			   } `json:"constraint"`
+}
+
+// PageRuleAction type, a single setting override applied by a PageRule
+type PageRuleAction struct {
+	Id    string      `json:"id"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// PageRule type
+type PageRule struct {
+	Id         string           `json:"id,omitempty"`
+	Targets    []PageRuleTarget `json:"targets"`
+	Actions    []PageRuleAction `json:"actions"`
+	Priority   int              `json:"priority,omitempty"`
+	Status     string           `json:"status,omitempty"`
+	ModifiedOn string           `json:"modified_on,omitempty"`
+	CreatedOn  string           `json:"created_on,omitempty"`
+}
+
+// Stringer Implementation for PageRule
+func (p PageRule) String() string {
+	return JsonString(p)
+}
+
+// PageRulesSvc Factory, pass config to inner BaseSvc object
+func (config *Config) GetPageRulesSvc() *PageRulesSvc {
+	return &PageRulesSvc{BaseSvc: BaseSvc{config: config}}
+}
+
+// Get all PageRules for zone
+//	Usage:
+//		pageRulesSvc := config.GetPageRulesSvc()
+//		pageRules, err := pageRulesSvc.Get(zone.Id)
+func (pageRulesSvc PageRulesSvc) Get(zoneId string) (pageRules []PageRule, err error) {
+	resp, err := pageRulesSvc.Invoke("GET", ApiUrlBase+APiUrlZones+zoneId+ApiUrlPageRules, nil)
+	if resp == nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	err = pageRulesSvc.Decode(resp, &pageRules)
+	return pageRules, err
+}
+
+// Create new PageRule for zone
+//	Usage:
+//		pageRulesSvc := config.GetPageRulesSvc()
+//		pageRule := &cloudflare.PageRule{Targets: targets, Actions: actions, Priority: 1, Status: "active"}
+//		res, err := pageRulesSvc.Create(zone.Id, pageRule)
+func (pageRulesSvc PageRulesSvc) Create(zoneId string, pageRule *PageRule) (res PageRule, err error) {
+	resp, err := pageRulesSvc.Invoke("POST", ApiUrlBase+APiUrlZones+zoneId+ApiUrlPageRules, strings.NewReader(pageRule.String()))
+	if resp == nil {
+		return PageRule{}, err
+	}
+	defer resp.Body.Close()
+	err = pageRulesSvc.Decode(resp, &res)
+	return res, err
+}
+
+// Update PageRule for zone
+//	Usage:
+//		pageRulesSvc := config.GetPageRulesSvc()
+//		pageRule := &cloudflare.PageRule{Id: [existing page rule id], Priority: 2}
+//		res, err := pageRulesSvc.Update(zone.Id, pageRule)
+func (pageRulesSvc PageRulesSvc) Update(zoneId string, pageRule *PageRule) (res PageRule, err error) {
+	resp, err := pageRulesSvc.Invoke("PUT", ApiUrlBase+APiUrlZones+zoneId+ApiUrlPageRules+pageRule.Id, strings.NewReader(pageRule.String()))
+	if resp == nil {
+		return PageRule{}, err
+	}
+	defer resp.Body.Close()
+	err = pageRulesSvc.Decode(resp, &res)
+	return res, err
+}
+
+// Delete PageRule for zone
+//	Usage:
+//		pageRulesSvc := config.GetPageRulesSvc()
+//		pageRule := &cloudflare.PageRule{Id: [existing page rule id]}
+//		id, err := pageRulesSvc.Delete(zone.Id, pageRule)
+func (pageRulesSvc PageRulesSvc) Delete(zoneId string, pageRule *PageRule) (id string, err error) {
+	resp, err := pageRulesSvc.Invoke("DELETE", ApiUrlBase+APiUrlZones+zoneId+ApiUrlPageRules+pageRule.Id, nil)
+	if resp == nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var retPageRule PageRule
+	err = pageRulesSvc.Decode(resp, &retPageRule)
+	return pageRule.Id, err
+}
@@ -0,0 +1,147 @@
+package cloudflare
+import (
+	"strings"
+)
+
+const ApiUrlRedirectsEntrypoint string = "/rulesets/phases/http_request_dynamic_redirect/entrypoint"
+const ApiUrlRulesets            string = "/rulesets/"
+const ApiUrlRulesetRules        string = "/rules/"
+
+// RedirectsSvc Service, backed by the Rulesets API's dynamic-redirect phase entrypoint
+type RedirectsSvc struct {
+	BaseSvc
+}
+
+// rulesetWire mirrors the Rulesets API's entrypoint response: the concrete
+// ruleset backing the phase, plus its rules. Create/Update/Delete mutate
+// individual rules under /rulesets/{id}/rules, not the phase-entrypoint
+// path itself, so callers need the ruleset id to do anything but Get.
+type rulesetWire struct {
+	Id    string         `json:"id"`
+	Rules []RedirectRule `json:"rules"`
+}
+
+// redirectFromValue type, the nested shape the Rulesets API expects for a redirect action
+type redirectFromValue struct {
+	TargetUrl struct {
+				   Value string `json:"value"`
+			   } `json:"target_url"`
+	StatusCode          int  `json:"status_code,omitempty"`
+	PreserveQueryString bool `json:"preserve_query_string,omitempty"`
+	PreservePathSuffix  bool `json:"preserve_path_suffix,omitempty"`
+}
+
+// RedirectRule type, a single Single Redirects rule
+type RedirectRule struct {
+	Id               string `json:"id,omitempty"`
+	Description      string `json:"description,omitempty"`
+	Expression       string `json:"expression"`
+	Action           string `json:"action"`
+	ActionParameters struct {
+						 FromValue redirectFromValue `json:"from_value"`
+					 } `json:"action_parameters"`
+	Status string `json:"status,omitempty"`
+}
+
+// NewRedirectRule builds a RedirectRule matching sourceExpression and redirecting to targetUrl
+//	Usage:
+//		rule := cloudflare.NewRedirectRule(`http.request.full_uri eq "https://old.example.com/"`, "https://new.example.com/", 301, true, false)
+func NewRedirectRule(sourceExpression string, targetUrl string, statusCode int, preserveQueryString bool, preservePathSuffix bool) RedirectRule {
+	rule := RedirectRule{Expression: sourceExpression, Action: "redirect"}
+	rule.ActionParameters.FromValue.TargetUrl.Value = targetUrl
+	rule.ActionParameters.FromValue.StatusCode = statusCode
+	rule.ActionParameters.FromValue.PreserveQueryString = preserveQueryString
+	rule.ActionParameters.FromValue.PreservePathSuffix = preservePathSuffix
+	return rule
+}
+
+// Stringer Implementation for RedirectRule
+func (r RedirectRule) String() string {
+	return JsonString(r)
+}
+
+// RedirectsSvc Factory, pass config to inner BaseSvc object
+func (config *Config) GetRedirectsSvc() *RedirectsSvc {
+	return &RedirectsSvc{BaseSvc: BaseSvc{config: config}}
+}
+
+// ruleset resolves the concrete ruleset backing the dynamic-redirect phase
+// entrypoint for zoneId, which Create/Update/Delete need in order to
+// address /rulesets/{id}/rules - the phase-entrypoint path itself only
+// supports whole-ruleset GET/PUT, not per-rule mutation
+func (redirectsSvc RedirectsSvc) ruleset(zoneId string) (ruleset rulesetWire, err error) {
+	resp, err := redirectsSvc.Invoke("GET", ApiUrlBase+APiUrlZones+zoneId+ApiUrlRedirectsEntrypoint, nil)
+	if resp == nil {
+		return rulesetWire{}, err
+	}
+	defer resp.Body.Close()
+	err = redirectsSvc.Decode(resp, &ruleset)
+	return ruleset, err
+}
+
+// Get all RedirectRules for zone
+//	Usage:
+//		redirectsSvc := config.GetRedirectsSvc()
+//		rules, err := redirectsSvc.Get(zone.Id)
+func (redirectsSvc RedirectsSvc) Get(zoneId string) (rules []RedirectRule, err error) {
+	ruleset, err := redirectsSvc.ruleset(zoneId)
+	return ruleset.Rules, err
+}
+
+// Create new RedirectRule for zone
+//	Usage:
+//		redirectsSvc := config.GetRedirectsSvc()
+//		rule := cloudflare.NewRedirectRule(expression, targetUrl, 301, true, false)
+//		res, err := redirectsSvc.Create(zone.Id, &rule)
+func (redirectsSvc RedirectsSvc) Create(zoneId string, rule *RedirectRule) (res RedirectRule, err error) {
+	ruleset, err := redirectsSvc.ruleset(zoneId)
+	if err != nil {
+		return RedirectRule{}, err
+	}
+	resp, err := redirectsSvc.Invoke("POST", ApiUrlBase+APiUrlZones+zoneId+ApiUrlRulesets+ruleset.Id+ApiUrlRulesetRules, strings.NewReader(rule.String()))
+	if resp == nil {
+		return RedirectRule{}, err
+	}
+	defer resp.Body.Close()
+	err = redirectsSvc.Decode(resp, &res)
+	return res, err
+}
+
+// Update RedirectRule for zone
+//	Usage:
+//		redirectsSvc := config.GetRedirectsSvc()
+//		rule := &cloudflare.RedirectRule{Id: [existing rule id], Expression: expression}
+//		res, err := redirectsSvc.Update(zone.Id, rule)
+func (redirectsSvc RedirectsSvc) Update(zoneId string, rule *RedirectRule) (res RedirectRule, err error) {
+	ruleset, err := redirectsSvc.ruleset(zoneId)
+	if err != nil {
+		return RedirectRule{}, err
+	}
+	resp, err := redirectsSvc.Invoke("PUT", ApiUrlBase+APiUrlZones+zoneId+ApiUrlRulesets+ruleset.Id+ApiUrlRulesetRules+rule.Id, strings.NewReader(rule.String()))
+	if resp == nil {
+		return RedirectRule{}, err
+	}
+	defer resp.Body.Close()
+	err = redirectsSvc.Decode(resp, &res)
+	return res, err
+}
+
+// Delete RedirectRule for zone
+//	Usage:
+//		redirectsSvc := config.GetRedirectsSvc()
+//		rule := &cloudflare.RedirectRule{Id: [existing rule id]}
+//		id, err := redirectsSvc.Delete(zone.Id, rule)
+func (redirectsSvc RedirectsSvc) Delete(zoneId string, rule *RedirectRule) (id string, err error) {
+	ruleset, err := redirectsSvc.ruleset(zoneId)
+	if err != nil {
+		return "", err
+	}
+	resp, err := redirectsSvc.Invoke("DELETE", ApiUrlBase+APiUrlZones+zoneId+ApiUrlRulesets+ruleset.Id+ApiUrlRulesetRules+rule.Id, nil)
+	if resp == nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var retRule RedirectRule
+	err = redirectsSvc.Decode(resp, &retRule)
+	return rule.Id, err
+}
@@ -0,0 +1,367 @@
+package cloudflare
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ImportOpts controls how ImportBIND reconciles a parsed zonefile against
+// the live zone
+type ImportOpts struct {
+	Prune bool // delete live records that are absent from the zonefile
+}
+
+// ImportAction describes what ImportBIND did with a single record
+type ImportAction string
+
+const (
+	ImportActionUnchanged ImportAction = "unchanged"
+	ImportActionCreated   ImportAction = "created"
+	ImportActionUpdated   ImportAction = "updated"
+	ImportActionDeleted   ImportAction = "deleted"
+)
+
+// ImportResult is the outcome of reconciling a single record
+type ImportResult struct {
+	Record DNSRecord
+	Action ImportAction
+	Error  error
+}
+
+// ImportReport summarizes an ImportBIND run
+type ImportReport struct {
+	Results          []ImportResult
+	UnchangedCount   int
+	CreatedCount     int
+	UpdatedCount     int
+	DeletedCount     int
+	ErrorCount       int
+}
+
+// record appends a result to the report and keeps the per-action counters in sync
+func (report *ImportReport) record(rec DNSRecord, action ImportAction, err error) {
+	report.Results = append(report.Results, ImportResult{Record: rec, Action: action, Error: err})
+	if err != nil {
+		report.ErrorCount++
+		return
+	}
+	switch action {
+	case ImportActionUnchanged:
+		report.UnchangedCount++
+	case ImportActionCreated:
+		report.CreatedCount++
+	case ImportActionUpdated:
+		report.UpdatedCount++
+	case ImportActionDeleted:
+		report.DeletedCount++
+	}
+}
+
+// ExportBIND writes zoneId's DNS records out in RFC 1035 zonefile format
+//	Usage:
+//		dnsSvc := config.GetDNSRecordsSvc()
+//		err := dnsSvc.ExportBIND(zone.Id, os.Stdout)
+func (dnsSvc DNSRecordsSvc) ExportBIND(zoneId string, w io.Writer) error {
+	resp, err := dnsSvc.Invoke("GET", ApiUrlBase+APiUrlZones+zoneId+ApiUrlDnsRecords+"export", nil)
+	if resp == nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cloudflare: zone export failed with status %d", resp.StatusCode)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// ImportBIND parses a RFC 1035 zonefile from r and reconciles it against
+// the live zone: unchanged records are skipped, changed records are
+// Updated in place, new records are Created, and when opts.Prune is set,
+// live records absent from the zonefile are Deleted
+//	Usage:
+//		dnsSvc := config.GetDNSRecordsSvc()
+//		report, err := dnsSvc.ImportBIND(zone.Id, file, cloudflare.ImportOpts{Prune: true})
+func (dnsSvc DNSRecordsSvc) ImportBIND(zoneId string, r io.Reader, opts ImportOpts) (report ImportReport, err error) {
+	parsed, err := parseZonefile(r)
+	if err != nil {
+		return report, err
+	}
+
+	live, err := dnsSvc.GetAll(zoneId)
+	if err != nil {
+		return report, err
+	}
+	liveByKey := make(map[string][]DNSRecord, len(live))
+	for _, rec := range live {
+		key := recordKey(rec)
+		liveByKey[key] = append(liveByKey[key], rec)
+	}
+
+	for _, rec := range parsed {
+		key := recordKey(rec)
+		candidates := liveByKey[key]
+		if len(candidates) == 0 {
+			created, cerr := dnsSvc.Create(zoneId, &rec)
+			report.record(created, ImportActionCreated, cerr)
+			continue
+		}
+
+		existing := candidates[0]
+		liveByKey[key] = candidates[1:]
+		if recordsEqual(existing, rec) {
+			report.record(existing, ImportActionUnchanged, nil)
+			continue
+		}
+		rec.Id = existing.Id
+		rec.Proxiable = existing.Proxiable
+		rec.Proxied = existing.Proxied
+		updated, uerr := dnsSvc.Update(zoneId, &rec)
+		report.record(updated, ImportActionUpdated, uerr)
+	}
+
+	if opts.Prune {
+		for _, candidates := range liveByKey {
+			for _, rec := range candidates {
+				_, derr := dnsSvc.Delete(zoneId, &rec)
+				report.record(rec, ImportActionDeleted, derr)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// recordKey identifies a DNSRecord by name+type for reconciliation matching.
+// Records sharing a key are matched positionally against the order they were
+// fetched/parsed in, since Cloudflare allows multiple records with the same
+// name and type (round-robin A records, multiple NS/MX/TXT entries, etc).
+func recordKey(rec DNSRecord) string {
+	return strings.ToLower(rec.Name) + "|" + strings.ToUpper(rec.Type)
+}
+
+// recordsEqual reports whether a live record already matches a parsed
+// zonefile record, ignoring fields the zonefile can't express
+func recordsEqual(live DNSRecord, parsed DNSRecord) bool {
+	if live.Content != parsed.Content {
+		return false
+	}
+	return parsed.Ttl == 0 || live.Ttl == parsed.Ttl
+}
+
+// parseZonefile parses RFC 1035 zonefile syntax into DNSRecords, handling
+// $ORIGIN/$TTL directives, comments, name-continuation lines, and BIND's
+// parenthesized multi-line records (Cloudflare's own zone export wraps the
+// SOA record this way). It does not support $INCLUDE. SOA records are
+// parsed only far enough to be skipped - Cloudflare doesn't allow mutating
+// a zone's SOA through this API, and the zonefile's own serial/refresh/
+// retry/expire/minimum fields don't map onto DNSRecord anyway.
+func parseZonefile(r io.Reader) (records []DNSRecord, err error) {
+	origin := ""
+	defaultTtl := uint64(0)
+	lastName := ""
+
+	scanner := bufio.NewScanner(r)
+	var logical strings.Builder
+	parenDepth := 0
+	continuesName := false
+	for scanner.Scan() {
+		rawLine := scanner.Text()
+		if parenDepth == 0 && logical.Len() == 0 {
+			continuesName = len(rawLine) > 0 && (rawLine[0] == ' ' || rawLine[0] == '\t')
+		}
+
+		line := strings.TrimSpace(stripZonefileComment(rawLine))
+		if line == "" && parenDepth == 0 {
+			continue
+		}
+
+		if logical.Len() > 0 {
+			logical.WriteByte(' ')
+		}
+		logical.WriteString(line)
+		parenDepth += strings.Count(line, "(") - strings.Count(line, ")")
+		if parenDepth > 0 {
+			continue
+		}
+
+		fullLine := stripZonefileParens(logical.String())
+		logical.Reset()
+		if fullLine == "" {
+			continue
+		}
+
+		if strings.HasPrefix(fullLine, "$ORIGIN") {
+			if fields := strings.Fields(fullLine); len(fields) >= 2 {
+				origin = strings.TrimSuffix(fields[1], ".")
+			}
+			continue
+		}
+		if strings.HasPrefix(fullLine, "$TTL") {
+			if fields := strings.Fields(fullLine); len(fields) >= 2 {
+				if ttl, ok := parseZonefileTTL(fields[1]); ok {
+					defaultTtl = ttl
+				}
+			}
+			continue
+		}
+
+		fields := tokenizeZonefileLine(fullLine)
+		if len(fields) == 0 {
+			continue
+		}
+
+		idx := 0
+		name := lastName
+		if !continuesName {
+			name = fields[0]
+			idx = 1
+		}
+		if idx >= len(fields) {
+			continue
+		}
+
+		ttl := defaultTtl
+		for idx < len(fields) {
+			if fields[idx] == "IN" {
+				idx++
+				continue
+			}
+			if val, ok := parseZonefileTTL(fields[idx]); ok {
+				ttl = val
+				idx++
+				continue
+			}
+			break
+		}
+		if idx >= len(fields) {
+			continue
+		}
+
+		recType := fields[idx]
+		idx++
+		lastName = name
+		if recType == "SOA" {
+			continue
+		}
+
+		content := strings.TrimSuffix(strings.Join(fields[idx:], " "), ".")
+		if recType == "TXT" {
+			content = strings.Trim(content, "\"")
+		}
+
+		records = append(records, DNSRecord{
+			Name:    qualifyZonefileName(name, origin),
+			Type:    recType,
+			Content: content,
+			Ttl:     ttl,
+		})
+	}
+	if parenDepth > 0 {
+		return records, fmt.Errorf("cloudflare: unterminated \"(\" in zonefile")
+	}
+	return records, scanner.Err()
+}
+
+// zonefileTTLUnits maps BIND's time-unit suffixes (RFC 2308 / BIND 8+) to seconds
+var zonefileTTLUnits = map[byte]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+// parseZonefileTTL parses a TTL field, accepting plain seconds ("3600") as
+// well as BIND's human-readable unit suffix form ("1h", "30m", "2w")
+func parseZonefileTTL(token string) (uint64, bool) {
+	if token == "" {
+		return 0, false
+	}
+	last := token[len(token)-1]
+	if last >= '0' && last <= '9' {
+		val, err := strconv.ParseUint(token, 10, 64)
+		return val, err == nil
+	}
+	unit, ok := zonefileTTLUnits[last|0x20]
+	if !ok {
+		return 0, false
+	}
+	val, err := strconv.ParseUint(token[:len(token)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return val * unit, true
+}
+
+// qualifyZonefileName expands "@" and relative names against origin,
+// leaving already fully-qualified ("trailing dot") names untouched
+func qualifyZonefileName(name string, origin string) string {
+	if name == "@" {
+		return origin
+	}
+	if strings.HasSuffix(name, ".") {
+		return strings.TrimSuffix(name, ".")
+	}
+	if origin == "" {
+		return name
+	}
+	return name + "." + origin
+}
+
+// stripZonefileParens blanks out BIND's "(" / ")" line-continuation
+// markers, respecting quoted strings so literal parens in TXT content
+// survive
+func stripZonefileParens(line string) string {
+	var b strings.Builder
+	inQuotes := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			b.WriteRune(r)
+		case (r == '(' || r == ')') && !inQuotes:
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// stripZonefileComment trims a trailing ";" comment, respecting quoted strings
+func stripZonefileComment(line string) string {
+	inQuotes := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ';':
+			if !inQuotes {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// tokenizeZonefileLine splits a zonefile line on whitespace, keeping quoted
+// strings (e.g. TXT content) together as a single field
+func tokenizeZonefileLine(line string) (fields []string) {
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}